@@ -2,8 +2,12 @@ package busservice
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"sync"
 	"time"
+
+	"github.com/mkock/busproject/busservice/clock"
 )
 
 // SeniorAge is the minimum age from which a Passenger is considered a senior to the BusCompany.
@@ -75,96 +79,378 @@ func (p Passengers) Manifest() []string {
 	return ssns
 }
 
+// ArrivalStatus describes how a StopVisit compared to its scheduled time.
+type ArrivalStatus int
+
+const (
+	// StatusOnTime means the Bus arrived or departed exactly on schedule.
+	StatusOnTime ArrivalStatus = iota
+	// StatusDelayed means the Bus arrived or departed later than scheduled.
+	StatusDelayed
+	// StatusEarly means the Bus arrived or departed earlier than scheduled.
+	StatusEarly
+	// StatusCancelled means the visit never happened.
+	StatusCancelled
+)
+
+// String returns a human-readable name for the ArrivalStatus.
+func (s ArrivalStatus) String() string {
+	switch s {
+	case StatusOnTime:
+		return "on-time"
+	case StatusDelayed:
+		return "delayed"
+	case StatusEarly:
+		return "early"
+	case StatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// ScheduledStop is a single entry in a Bus's Timetable: a BusStop the Bus is due to visit,
+// the time it's scheduled to arrive, and how long it's scheduled to dwell before departing.
+type ScheduledStop struct {
+	Stop             *BusStop
+	ScheduledArrival time.Time
+	Dwell            time.Duration
+}
+
+// Timetable is the ordered schedule that a Bus follows, one ScheduledStop per stop on its route.
+type Timetable []ScheduledStop
+
+// StopVisit records what actually happened when a Bus visited a scheduled stop, so that delays
+// and early/on-time performance can be observed after the fact.
+type StopVisit struct {
+	Stop              *BusStop
+	ScheduledArrival  time.Time
+	RecordedArrival   time.Time
+	ArrivalStatus     ArrivalStatus
+	RecordedDeparture time.Time
+	DepartureStatus   ArrivalStatus
+}
+
 // Bus carries Passengers from A to B if they have a valid bus ticket.
+// A Bus must be accessed through a pointer: it holds a sync.RWMutex so that an HTTP layer
+// serving many requests at once (see busservice/httpapi) can query and update it concurrently.
 type Bus struct {
 	Company     BusCompany
+	Capacity    int
+	mu          sync.RWMutex
 	name        string
 	passengers  Passengers
 	stops       []*BusStop
 	currentStop int16
+	clock       clock.Clock
+	timetable   Timetable
+	visits      []StopVisit
 }
 
-// NewBus returns a new Bus with an empty passenger set.
-func NewBus(name string) Bus {
-	b := Bus{}
+// NewBus returns a new Bus with an empty passenger set and no Capacity limit.
+func NewBus(name string) *Bus {
+	b := &Bus{}
 	b.name = name
 	b.currentStop = -1
 	b.passengers = NewPassengerSet()
+	b.clock = clock.Real{}
+	return b
+}
+
+// NewBusWithCapacity returns a new Bus with an empty passenger set that refuses boarding once
+// Capacity Passengers are aboard. A Capacity of 0 means unlimited, matching NewBus.
+func NewBusWithCapacity(name string, capacity int) *Bus {
+	b := NewBus(name)
+	b.Capacity = capacity
 	return b
 }
 
 // add adds a single Passenger to the Bus. For brevity, we don't care too much about accidentally adding the same Passenger more than once.
-func (b *Bus) add(p Passenger) {
+// add inserts p into the Bus's passengers, re-checking capacity under the same lock so that
+// concurrent Boards can't both pass a capacity check taken earlier and jointly overfill the bus.
+// It returns false, leaving the Bus unchanged, if the Bus is already at capacity.
+func (b *Bus) add(p Passenger) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Capacity > 0 && len(b.passengers) >= b.Capacity {
+		return false
+	}
 	if b.passengers == nil {
 		b.passengers = make(map[string]Passenger)
 	}
 	b.passengers[p.SSN] = p
 	fmt.Printf("%s: boarded passenger with SSN %q\n", b.name, p.SSN)
+	return true
 }
 
 // Board adds the given Passenger to the Bus and charges them a ticket price calculated by chargeFn if they don't already have a paid ticket.
 // Board returns false if the Passenger was not allowed to board the Bus.
 func (b *Bus) Board(p *Passenger, chargeFn PriceCalculator) bool {
-	var allowed bool // Default value is false
-	if p.HasValidTicket {
-		allowed = true
-	} else {
-		amount := chargeFn(*p)
+	b.mu.RLock()
+	full := b.Capacity > 0 && len(b.passengers) >= b.Capacity
+	b.mu.RUnlock()
+	if full {
+		fmt.Printf("%s: refused to board passenger with SSN %q: bus is at capacity\n", b.name, p.SSN)
+		return false
+	}
+
+	if !p.HasValidTicket {
+		amount := chargeFn(*p) // chargeFn may itself call back into the Bus, e.g. DistancePricing reads CurrentStop.
 		p2 := p.Charge(amount)
 		p = &p2
-		allowed = true
 	}
-	if allowed {
-		b.add(*p)
+	if !b.add(*p) {
+		fmt.Printf("%s: refused to board passenger with SSN %q: bus is at capacity\n", b.name, p.SSN)
+		return false
 	}
-	return allowed
+	return true
 }
 
 // Remove removes a single Passenger from the Bus.
 func (b *Bus) Remove(p Passenger) {
+	b.mu.Lock()
 	delete(b.passengers, p.SSN)
+	b.mu.Unlock()
 	fmt.Printf("%s: unboarded passenger with SSN %q\n", b.name, p.SSN)
 }
 
-// AddStop adds the given BusStop to the list of stops that the Bus will stop at. Each stop is visited in order.
+// AddStop adds the given BusStop to the list of stops that the Bus will stop at. Each stop is
+// visited in order, and the BusStop is told that this Bus now services it.
 func (b *Bus) AddStop(busStop *BusStop) {
+	b.mu.Lock()
 	b.stops = append(b.stops, busStop)
+	b.mu.Unlock()
+	busStop.registerBus(b)
+}
+
+// Stops returns the ordered list of BusStops that the Bus will visit.
+func (b *Bus) Stops() []*BusStop {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]*BusStop, len(b.stops))
+	copy(out, b.stops)
+	return out
+}
+
+// SetTimetable assigns the schedule that Go consults to evaluate on-time performance as the Bus
+// advances, replacing any stops added via AddStop with the Timetable's own stops so the two stay
+// in sync.
+func (b *Bus) SetTimetable(tt Timetable) {
+	b.mu.Lock()
+	b.timetable = tt
+	b.stops = make([]*BusStop, len(tt))
+	for i, ss := range tt {
+		b.stops[i] = ss.Stop
+	}
+	b.visits = make([]StopVisit, 0, len(tt))
+	b.mu.Unlock()
+
+	for _, ss := range tt {
+		ss.Stop.registerBus(b)
+	}
+}
+
+// SetClock injects the clock.Clock that Go consults to time StopVisits. Simulations and tests
+// can supply a clock.Manual to control virtual time precisely; the default is clock.Real.
+func (b *Bus) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	b.clock = c
+	b.mu.Unlock()
+}
+
+// Visits returns the StopVisit history recorded as the Bus advanced along its Timetable.
+func (b *Bus) Visits() []StopVisit {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	visits := make([]StopVisit, len(b.visits))
+	copy(visits, b.visits)
+	return visits
+}
+
+// Timetable returns the schedule assigned via SetTimetable, or nil if none was assigned.
+func (b *Bus) Timetable() Timetable {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.timetable == nil {
+		return nil
+	}
+	tt := make(Timetable, len(b.timetable))
+	copy(tt, b.timetable)
+	return tt
+}
+
+// Name returns the Bus's name.
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// Delay returns how far behind schedule the Bus was upon arrival at the given BusStop. A
+// negative duration means the Bus arrived early. Delay returns 0 if the stop wasn't visited
+// (including a stop passed over by SkipStop), or if the Bus has no Timetable.
+func (b *Bus) Delay(stop *BusStop) time.Duration {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, v := range b.visits {
+		if v.Stop.Equals(stop) {
+			if v.ArrivalStatus == StatusCancelled {
+				return 0
+			}
+			return v.RecordedArrival.Sub(v.ScheduledArrival)
+		}
+	}
+	return 0
+}
+
+// now returns the current time as seen by the Bus's clock, defaulting to the real clock if none
+// was ever set.
+func (b *Bus) now() time.Time {
+	if b.clock == nil {
+		return time.Now()
+	}
+	return b.clock.Now()
+}
+
+// compareToSchedule classifies actual against scheduled as on-time, delayed or early.
+func compareToSchedule(scheduled, actual time.Time) ArrivalStatus {
+	switch {
+	case actual.After(scheduled):
+		return StatusDelayed
+	case actual.Before(scheduled):
+		return StatusEarly
+	default:
+		return StatusOnTime
+	}
+}
+
+// departCurrentStop closes out the StopVisit for the Bus's current position, if any, before it
+// advances to the next stop.
+func (b *Bus) departCurrentStop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timetable == nil || b.currentStop < 0 || int(b.currentStop) >= len(b.visits) {
+		return
+	}
+	sched := b.timetable[b.currentStop]
+	now := b.now()
+	v := &b.visits[b.currentStop]
+	v.RecordedDeparture = now
+	v.DepartureStatus = compareToSchedule(sched.ScheduledArrival.Add(sched.Dwell), now)
+}
+
+// arriveCurrentStop records a StopVisit for the Bus's current position against its Timetable,
+// if any.
+func (b *Bus) arriveCurrentStop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timetable == nil || int(b.currentStop) >= len(b.timetable) {
+		return
+	}
+	sched := b.timetable[b.currentStop]
+	now := b.now()
+	b.visits = append(b.visits, StopVisit{
+		Stop:             sched.Stop,
+		ScheduledArrival: sched.ScheduledArrival,
+		RecordedArrival:  now,
+		ArrivalStatus:    compareToSchedule(sched.ScheduledArrival, now),
+	})
+}
+
+// SkipStop advances the Bus past its next scheduled stop without actually visiting it,
+// recording a StopVisit with StatusCancelled so that the omission shows up in Visits; Delay
+// reports 0 for the skipped stop.
+// SkipStop returns true if there are still more stops to visit, matching Go's return convention.
+// It is a no-op returning false if the Bus has no Timetable or has already reached the end of
+// the line.
+func (b *Bus) SkipStop() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timetable == nil {
+		return false
+	}
+	next := b.currentStop + 1
+	if int(next) >= len(b.timetable) {
+		return false
+	}
+	sched := b.timetable[next]
+	now := b.now()
+	b.currentStop = next
+	b.visits = append(b.visits, StopVisit{
+		Stop:              sched.Stop,
+		ScheduledArrival:  sched.ScheduledArrival,
+		RecordedArrival:   now,
+		ArrivalStatus:     StatusCancelled,
+		RecordedDeparture: now,
+		DepartureStatus:   StatusCancelled,
+	})
+	lastIndex := int16(len(b.stops) - 1)
+	return b.currentStop < lastIndex
 }
 
 // Go takes the Bus to the next BusStop. Go returns true if there are still more stops to visit.
 func (b *Bus) Go() bool {
+	b.departCurrentStop()
+
+	b.mu.Lock()
 	b.currentStop++
+	currentStop := b.currentStop
 	lastIndex := int16(len(b.stops) - 1)
-	if b.currentStop == lastIndex {
+	b.mu.Unlock()
+
+	b.arriveCurrentStop()
+	if currentStop == lastIndex {
 		fmt.Printf("%s: reached the end of the line, everybody out\n", b.name)
 		b.VisitPassengers(func(p Passenger) {
 			b.Remove(p)
 		})
 		return false
 	}
-	if b.currentStop == 0 {
+	if currentStop == 0 {
 		fmt.Printf("%s: starting\n", b.name)
 	} else {
-		fmt.Printf("%s: carrying %d passengers: heading for next stop\n", b.name, len(b.passengers))
+		fmt.Printf("%s: carrying %d passengers: heading for next stop\n", b.name, len(b.Manifest()))
+	}
+	curr := b.CurrentStop()
+	if curr == nil {
+		return currentStop < lastIndex
 	}
-	curr := b.stops[b.currentStop]
 	fmt.Printf("%s: arriving at %q\n", b.name, curr.Name)
 	curr.NotifyBusArrival(b)
-	return b.currentStop < lastIndex
+	return currentStop < lastIndex
 }
 
 // Manifest asks Passengers for a SSN manifest and returns it.
-func (b Bus) Manifest() []string {
+func (b *Bus) Manifest() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	return b.passengers.Manifest()
 }
 
+// RoutePath returns the ordered geographic path of the Bus's stops, mirroring how transit
+// APIs return a sequence of LatLng points along a line.
+func (b *Bus) RoutePath() []*BusStop {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	path := make([]*BusStop, len(b.stops))
+	copy(path, b.stops)
+	return path
+}
+
 // VisitPassengers calls function visitor for each Passenger on the bus.
 func (b *Bus) VisitPassengers(visitor func(Passenger)) {
-	b.passengers.Visit(visitor)
+	b.mu.RLock()
+	list := make([]Passenger, 0, len(b.passengers))
+	b.passengers.Visit(func(p Passenger) { list = append(list, p) })
+	b.mu.RUnlock()
+
+	for _, p := range list {
+		visitor(p)
+	}
 }
 
 // FindPassenger returns the Passenger that matches the given SSN, if found. Otherwise, an empty Passenger is returned.
 func (b *Bus) FindPassenger(ssn string) Passenger {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	if p, ok := b.passengers[ssn]; ok {
 		return p
 	}
@@ -173,6 +459,8 @@ func (b *Bus) FindPassenger(ssn string) Passenger {
 
 // UpdatePassengers calls function visitor for each Passenger on the bus. Passengers are passed by reference and may be modified.
 func (b *Bus) UpdatePassengers(visitor func(*Passenger)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	ps := make(map[string]Passenger, len(b.passengers))
 	for ssn, p := range b.passengers {
 		visitor(&p)
@@ -189,14 +477,20 @@ func (b *Bus) NotifyBoardingIntent(busStop *BusStop) {
 	b.AddStop(busStop)
 }
 
-// NotifyArrival notifies the current BusStop that the Bus has arrived.
+// NotifyArrival notifies the current BusStop that the Bus has arrived. It is a no-op if the Bus
+// hasn't departed yet.
 func (b *Bus) NotifyArrival() {
-	curr := b.stops[b.currentStop]
+	curr := b.CurrentStop()
+	if curr == nil {
+		return
+	}
 	curr.NotifyBusArrival(b)
 }
 
 // StopsAt checks if Bus stops at the given BusStop, and returns true if it does, and false otherwise.
-func (b Bus) StopsAt(busStop *BusStop) bool {
+func (b *Bus) StopsAt(busStop *BusStop) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	for _, stop := range b.stops {
 		if stop.Equals(busStop) {
 			return true
@@ -205,11 +499,47 @@ func (b Bus) StopsAt(busStop *BusStop) bool {
 	return false
 }
 
-// CurrentStop returns the BusStop that the Bus is currently stopped at.
-func (b Bus) CurrentStop() *BusStop {
+// CurrentStop returns the BusStop that the Bus is currently stopped at, or nil if the Bus
+// hasn't departed yet (or Go was called again after it reached the last stop). At the last
+// stop itself, CurrentStop returns that stop.
+func (b *Bus) CurrentStop() *BusStop {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.currentStop < 0 || int(b.currentStop) >= len(b.stops) {
+		return nil
+	}
 	return b.stops[b.currentStop]
 }
 
+// NextArrivals returns up to limit upcoming ScheduledStops for the given BusStop on this Bus's
+// Timetable, i.e. entries not yet visited. A limit of 0 means no limit.
+func (b *Bus) NextArrivals(stop *BusStop, limit int) []ScheduledStop {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var out []ScheduledStop
+	for i := int(b.currentStop) + 1; i < len(b.timetable); i++ {
+		ss := b.timetable[i]
+		if ss.Stop.Equals(stop) {
+			out = append(out, ss)
+			if limit > 0 && len(out) >= limit {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// NextStop returns the BusStop the Bus will arrive at next, or nil if it's at the end of the line.
+func (b *Bus) NextStop() *BusStop {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	next := b.currentStop + 1
+	if int(next) >= len(b.stops) {
+		return nil
+	}
+	return b.stops[next]
+}
+
 // Prospect is a potential Passenger. Prospects wait at BusStops to board Buses.
 type Prospect struct {
 	SSN         string
@@ -221,12 +551,43 @@ func (p Prospect) ToPassenger() Passenger {
 	return Passenger{SSN: p.SSN, Destination: p.Destination}
 }
 
+// earthRadiusKm is the radius of the earth in kilometers, used by Distance's haversine calculation.
+const earthRadiusKm = 6371.0
+
+// Position represents a geographic coordinate, expressed in decimal degrees.
+type Position struct {
+	Lat float64
+	Lon float64
+}
+
 // BusStop represents a place where a Bus can stop and signal to prospects (future passengers)
 // that they may board.
+// A BusStop is safe for concurrent use: it holds a sync.RWMutex so that an HTTP layer serving
+// many requests at once (see busservice/httpapi) can query and update it concurrently.
 type BusStop struct {
 	Name      string
+	Position  Position
+	mu        sync.RWMutex
 	prospects []Prospect
-	busses    []Bus
+	busses    []*Bus
+}
+
+// Distance returns the great-circle distance in kilometers between the receiver and other,
+// computed with the haversine formula from their Position.
+func (b *BusStop) Distance(other *BusStop) float64 {
+	lat1, lon1 := toRadians(b.Position.Lat), toRadians(b.Position.Lon)
+	lat2, lon2 := toRadians(other.Position.Lat), toRadians(other.Position.Lon)
+	dLat := lat2 - lat1
+	dLon := lon2 - lon1
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return earthRadiusKm * c
+}
+
+// toRadians converts a value in degrees to radians.
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
 }
 
 // Equals returns true if the given BusStop is the same as the receiver.
@@ -234,33 +595,125 @@ func (b *BusStop) Equals(busStop *BusStop) bool {
 	return b.Name == busStop.Name
 }
 
-// NotifyBusArrival is called by Bus upon arrival.
+// registerBus records that bus stops here, so that IntermediateStops and Prospect notifications
+// can find it. registerBus is idempotent.
+func (b *BusStop) registerBus(bus *Bus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, existing := range b.busses {
+		if existing == bus {
+			return
+		}
+	}
+	b.busses = append(b.busses, bus)
+}
+
+// IntermediateStops returns the ordered, inclusive slice of BusStops between the receiver and
+// dest, as found on the route of any Bus that stops at both. If more than one Bus qualifies,
+// the first one found is used. IntermediateStops returns an error if no Bus services both stops.
+func (b *BusStop) IntermediateStops(dest *BusStop) ([]*BusStop, error) {
+	b.mu.RLock()
+	busses := make([]*Bus, len(b.busses))
+	copy(busses, b.busses)
+	b.mu.RUnlock()
+
+	for _, bus := range busses {
+		if !bus.StopsAt(dest) {
+			continue
+		}
+		stops := bus.Stops()
+		from, to := -1, -1
+		for i, s := range stops {
+			if s.Equals(b) {
+				from = i
+			}
+			if s.Equals(dest) {
+				to = i
+			}
+		}
+		if from == -1 || to == -1 {
+			continue
+		}
+		if from <= to {
+			return stops[from : to+1], nil
+		}
+		rev := make([]*BusStop, 0, from-to+1)
+		for i := from; i >= to; i-- {
+			rev = append(rev, stops[i])
+		}
+		return rev, nil
+	}
+	return nil, fmt.Errorf("busservice: no bus services both %q and %q", b.Name, dest.Name)
+}
+
+// NotifyBusArrival is called by Bus upon arrival. Prospects that board leave the queue;
+// prospects that are refused (e.g. because the Bus is at capacity) stay queued in FIFO order
+// so that a later Bus on an overlapping line can pick them up.
 func (b *BusStop) NotifyBusArrival(bus *Bus) {
 	bus.VisitPassengers(func(p Passenger) {
-		if bus.CurrentStop().Equals(p.Destination) {
+		if cur := bus.CurrentStop(); cur != nil && cur.Equals(p.Destination) {
 			bus.Remove(p)
 		}
 	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var remaining []Prospect
 	for _, p := range b.prospects {
 		if bus.StopsAt(p.Destination) {
 			pas := p.ToPassenger()
-			bus.Board(&pas, bus.Company.GetPricing())
+			if bus.Board(&pas, bus.Company.GetPricing()) {
+				continue
+			}
 		}
+		remaining = append(remaining, p)
 	}
+	b.prospects = remaining
 }
 
 // NotifyProspectArrival is called whenever a prospect arrives at Busstop.
 func (b *BusStop) NotifyProspectArrival(p Prospect) {
+	b.mu.Lock()
 	b.prospects = append(b.prospects, p)
+	busses := make([]*Bus, len(b.busses))
+	copy(busses, b.busses)
+	b.mu.Unlock()
 
 	// Find all Busses on this route.
-	for _, bus := range b.busses {
+	for _, bus := range busses {
 		if bus.StopsAt(p.Destination) {
 			bus.NotifyBoardingIntent(b)
 		}
 	}
 }
 
+// Occupied returns true if one or more Prospects are currently waiting at the BusStop.
+func (b *BusStop) Occupied() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.prospects) > 0
+}
+
+// QueueLength returns the number of Prospects currently waiting at the BusStop.
+func (b *BusStop) QueueLength() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.prospects)
+}
+
+// NotifyProspectDeparture removes the Prospect with the given SSN from the queue, e.g. because
+// they balked after waiting too long for a Bus.
+func (b *BusStop) NotifyProspectDeparture(ssn string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, p := range b.prospects {
+		if p.SSN == ssn {
+			b.prospects = append(b.prospects[:i], b.prospects[i+1:]...)
+			return
+		}
+	}
+}
+
 // WorkdayPricing charges EUR 6 for regular Passengers and EUR 4.5 for seniors during workdays.
 func WorkdayPricing(p Passenger) float64 {
 	if p.IsSenior() {
@@ -277,6 +730,25 @@ func WeekendPricing(p Passenger) float64 {
 	return 5.0
 }
 
+// DistanceSeniorDiscount is the fraction knocked off a DistancePricing fare for senior Passengers.
+const DistanceSeniorDiscount = 0.25
+
+// DistancePricing returns a PriceCalculator that charges base plus perKm for every kilometer
+// between bus's current stop and the boarding Passenger's destination, with the usual senior
+// discount applied.
+func DistancePricing(base, perKm float64, bus *Bus) PriceCalculator {
+	return func(p Passenger) float64 {
+		amount := base
+		if cur := bus.CurrentStop(); cur != nil && p.Destination != nil {
+			amount += cur.Distance(p.Destination) * perKm
+		}
+		if p.IsSenior() {
+			amount *= 1 - DistanceSeniorDiscount
+		}
+		return amount
+	}
+}
+
 // PriceCalculator is the type used by BusCompany to determine the ticket price for a Passenger.
 // PriceCalculator returns the ticket price in the local currency.
 type PriceCalculator func(p Passenger) float64
@@ -0,0 +1,39 @@
+// Package clock abstracts time.Now so that simulations can inject virtual time and observe
+// how a Bus's schedule adherence evolves without waiting on the wall clock.
+package clock
+
+import "time"
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is a Clock backed by the system time.
+type Real struct{}
+
+// Now returns the current system time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Manual is a Clock whose time only changes when Advance is called, useful for simulations and
+// tests that need to control the passage of time precisely.
+type Manual struct {
+	now time.Time
+}
+
+// NewManual returns a Manual clock set to start.
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now returns the Manual clock's current time.
+func (m *Manual) Now() time.Time {
+	return m.now
+}
+
+// Advance moves the Manual clock's current time forward by d.
+func (m *Manual) Advance(d time.Duration) {
+	m.now = m.now.Add(d)
+}
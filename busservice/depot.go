@@ -0,0 +1,106 @@
+package busservice
+
+import (
+	"fmt"
+	"time"
+)
+
+// LineSchedule describes how often, in minutes, a line's Buses depart the depot.
+type LineSchedule struct {
+	Line   string
+	Period int
+}
+
+// OffsetConstraint requires the named line to depart exactly Offset minutes after the
+// synchronization time T that PlanSynchronizedDepartures solves for.
+type OffsetConstraint struct {
+	Line   string
+	Offset int
+}
+
+// PlanSynchronizedDepartures solves for the earliest time at or after simulationStart at which
+// every line in constraints can depart its depot exactly Offset minutes later, given each
+// line's departure Period in lines. It uses the Chinese Remainder Theorem: each constraint
+// requires T ≡ -offset_i (mod period_i), and the per-line congruences are combined pairwise via
+// the extended Euclidean algorithm. PlanSynchronizedDepartures returns an error if the periods
+// aren't pairwise compatible on the required residues, i.e. no such T exists.
+func PlanSynchronizedDepartures(simulationStart time.Time, lines []LineSchedule, constraints []OffsetConstraint) (time.Time, error) {
+	periods := make(map[string]int, len(lines))
+	for _, l := range lines {
+		periods[l.Line] = l.Period
+	}
+
+	var t, m int64 = 0, 1
+	for _, c := range constraints {
+		n, ok := periods[c.Line]
+		if !ok {
+			return time.Time{}, fmt.Errorf("busservice: no LineSchedule for line %q", c.Line)
+		}
+		a := int64(-c.Offset)
+
+		g, p, _ := extGCD(m, int64(n))
+		diff := a - t
+		if floorMod(diff, g) != 0 {
+			return time.Time{}, fmt.Errorf("busservice: no synchronized departure exists for line %q: periods aren't pairwise-compatible on that residue", c.Line)
+		}
+		lcm := m / g * int64(n)
+		t = floorMod(t+m*floorDiv(diff, g)*p, lcm)
+		m = lcm
+	}
+	return simulationStart.Add(time.Duration(t) * time.Minute), nil
+}
+
+// extGCD returns g = gcd(a, b) along with Bézout coefficients p, q such that a*p + b*q = g.
+func extGCD(a, b int64) (g, p, q int64) {
+	if b == 0 {
+		return a, 1, 0
+	}
+	g, p1, q1 := extGCD(b, a%b)
+	return g, q1, p1 - (a/b)*q1
+}
+
+// floorDiv returns a divided by b, rounded toward negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns a modulo b, with a result that always has the same sign as b.
+func floorMod(a, b int64) int64 {
+	m := a % b
+	if m != 0 && (m < 0) != (b < 0) {
+		m += b
+	}
+	return m
+}
+
+// Depot dispatches the Buses serving a set of lines, typically once
+// PlanSynchronizedDepartures has determined a staggered departure pattern for them.
+type Depot struct {
+	buses map[string]*Bus // keyed by line name
+}
+
+// NewDepot returns an empty Depot, ready to have Buses dispatched from it.
+func NewDepot() *Depot {
+	return &Depot{buses: make(map[string]*Bus)}
+}
+
+// Dispatch registers bus as serving the named line.
+func (d *Depot) Dispatch(line string, bus *Bus) {
+	d.buses[line] = bus
+}
+
+// Bus returns the Bus dispatched for the given line, if any.
+func (d *Depot) Bus(line string) (*Bus, bool) {
+	b, ok := d.buses[line]
+	return b, ok
+}
+
+// Plan computes the synchronized departure time for the given lines and constraints using
+// PlanSynchronizedDepartures. It does not consult the Buses dispatched from this Depot.
+func (d *Depot) Plan(simulationStart time.Time, lines []LineSchedule, constraints []OffsetConstraint) (time.Time, error) {
+	return PlanSynchronizedDepartures(simulationStart, lines, constraints)
+}
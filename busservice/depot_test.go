@@ -0,0 +1,55 @@
+package busservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanSynchronizedDepartures(t *testing.T) {
+	start := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	lines := []LineSchedule{
+		{Line: "A", Period: 4},
+		{Line: "B", Period: 6},
+	}
+	constraints := []OffsetConstraint{
+		{Line: "A", Offset: 2},
+		{Line: "B", Offset: 4},
+	}
+
+	got, err := PlanSynchronizedDepartures(start, lines, constraints)
+	if err != nil {
+		t.Fatalf("PlanSynchronizedDepartures() error = %v, want nil", err)
+	}
+
+	// T must satisfy T ≡ 2 (mod 4) and T ≡ 2 (mod 6); the earliest such T is 2 minutes in.
+	want := start.Add(2 * time.Minute)
+	if !got.Equal(want) {
+		t.Errorf("PlanSynchronizedDepartures() = %v, want %v", got, want)
+	}
+}
+
+func TestPlanSynchronizedDeparturesNoSolution(t *testing.T) {
+	start := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	lines := []LineSchedule{
+		{Line: "A", Period: 4},
+		{Line: "B", Period: 6},
+	}
+	constraints := []OffsetConstraint{
+		{Line: "A", Offset: 0},
+		{Line: "B", Offset: 1},
+	}
+
+	if _, err := PlanSynchronizedDepartures(start, lines, constraints); err == nil {
+		t.Fatal("PlanSynchronizedDepartures() error = nil, want an error for incompatible periods")
+	}
+}
+
+func TestPlanSynchronizedDeparturesUnknownLine(t *testing.T) {
+	start := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	lines := []LineSchedule{{Line: "A", Period: 4}}
+	constraints := []OffsetConstraint{{Line: "B", Offset: 0}}
+
+	if _, err := PlanSynchronizedDepartures(start, lines, constraints); err == nil {
+		t.Fatal("PlanSynchronizedDepartures() error = nil, want an error for an unknown line")
+	}
+}
@@ -0,0 +1,59 @@
+package busservice
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBusStopDistance(t *testing.T) {
+	// Copenhagen Central Station to Aarhus Station, roughly 156km as the crow flies.
+	cph := &BusStop{Name: "Copenhagen Central", Position: Position{Lat: 55.6727, Lon: 12.5646}}
+	aarhus := &BusStop{Name: "Aarhus Station", Position: Position{Lat: 56.1502, Lon: 10.2035}}
+
+	got := cph.Distance(aarhus)
+	const want = 156.4
+	if math.Abs(got-want) > 5 {
+		t.Errorf("Distance() = %.1f, want approximately %.1f", got, want)
+	}
+
+	if got := cph.Distance(cph); got != 0 {
+		t.Errorf("Distance() to self = %.4f, want 0", got)
+	}
+}
+
+func TestDistancePricing(t *testing.T) {
+	depot := &BusStop{Name: "Depot", Position: Position{Lat: 55.6727, Lon: 12.5646}}
+	dest := &BusStop{Name: "Aarhus Station", Position: Position{Lat: 56.1502, Lon: 10.2035}}
+
+	bus := NewBus("line-1")
+	bus.AddStop(depot)
+
+	price := DistancePricing(10, 1, bus)
+
+	t.Run("not yet departed charges base fare", func(t *testing.T) {
+		p := Passenger{SSN: "010101-0130", Destination: dest}
+		if got := price(p); got != 10 {
+			t.Errorf("price() = %.2f, want base fare 10.00", got)
+		}
+	})
+
+	bus.Go() // Depart the depot, arriving at the only stop on the route.
+
+	t.Run("charges base plus distance once departed", func(t *testing.T) {
+		p := Passenger{SSN: "010101-0130", Destination: dest}
+		dist := depot.Distance(dest)
+		want := 10 + dist
+		if got := price(p); math.Abs(got-want) > 0.01 {
+			t.Errorf("price() = %.2f, want %.2f", got, want)
+		}
+	})
+
+	t.Run("applies senior discount", func(t *testing.T) {
+		p := Passenger{SSN: "010101-0170", Destination: dest} // age 70, a senior.
+		dist := depot.Distance(dest)
+		want := (10 + dist) * (1 - DistanceSeniorDiscount)
+		if got := price(p); math.Abs(got-want) > 0.01 {
+			t.Errorf("price() = %.2f, want %.2f", got, want)
+		}
+	})
+}
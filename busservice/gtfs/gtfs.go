@@ -0,0 +1,385 @@
+// Package gtfs loads and dumps busservice simulations in the General Transit Feed Specification
+// (GTFS) format, so that the toy simulator can interoperate with real transit datasets and
+// unlocks realistic test fixtures.
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkock/busproject/busservice"
+)
+
+// csvTable is a GTFS .txt file parsed into rows keyed by column name.
+type csvTable []map[string]string
+
+// readTable reads the named file from the GTFS zip archive into a csvTable. Missing files
+// (e.g. an optional one) yield an empty table rather than an error.
+func readTable(r *zip.Reader, name string) (csvTable, error) {
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("gtfs: read %s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make(csvTable, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(rec) {
+				row[col] = rec[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Load reads a GTFS feed from the zip archive at path and builds one Bus per GTFS trip,
+// complete with its BusStops and Timetable.
+func Load(path string) ([]*busservice.Bus, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("gtfs: open %q: %w", path, err)
+	}
+	defer zr.Close()
+
+	stopRows, err := readTable(&zr.Reader, "stops.txt")
+	if err != nil {
+		return nil, err
+	}
+	routeRows, err := readTable(&zr.Reader, "routes.txt")
+	if err != nil {
+		return nil, err
+	}
+	tripRows, err := readTable(&zr.Reader, "trips.txt")
+	if err != nil {
+		return nil, err
+	}
+	stopTimeRows, err := readTable(&zr.Reader, "stop_times.txt")
+	if err != nil {
+		return nil, err
+	}
+	calendarRows, err := readTable(&zr.Reader, "calendar.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	stops := make(map[string]*busservice.BusStop, len(stopRows))
+	for _, row := range stopRows {
+		lat, _ := strconv.ParseFloat(row["stop_lat"], 64)
+		lon, _ := strconv.ParseFloat(row["stop_lon"], 64)
+		stops[row["stop_id"]] = &busservice.BusStop{
+			Name:     row["stop_name"],
+			Position: busservice.Position{Lat: lat, Lon: lon},
+		}
+	}
+
+	routeNames := make(map[string]string, len(routeRows))
+	for _, row := range routeRows {
+		name := row["route_long_name"]
+		if name == "" {
+			name = row["route_short_name"]
+		}
+		routeNames[row["route_id"]] = name
+	}
+
+	serviceStart := make(map[string]string, len(calendarRows))
+	for _, row := range calendarRows {
+		serviceStart[row["service_id"]] = row["start_date"]
+	}
+
+	type tripEntry struct {
+		bus       *busservice.Bus
+		timetable busservice.Timetable
+		sequence  []int
+		startDate string
+	}
+	trips := make(map[string]*tripEntry, len(tripRows))
+	order := make([]string, 0, len(tripRows))
+	for _, row := range tripRows {
+		tripID := row["trip_id"]
+		name := row["trip_headsign"]
+		if name == "" {
+			name = tripID
+		}
+		bus := busservice.NewBus(name)
+		bus.Company = busservice.BusCompany(routeNames[row["route_id"]])
+		trips[tripID] = &tripEntry{bus: bus, startDate: serviceStart[row["service_id"]]}
+		order = append(order, tripID)
+	}
+
+	for _, row := range stopTimeRows {
+		entry, ok := trips[row["trip_id"]]
+		if !ok {
+			continue
+		}
+		stop, ok := stops[row["stop_id"]]
+		if !ok {
+			continue
+		}
+		arrival, err := parseGTFSTime(entry.startDate, row["arrival_time"])
+		if err != nil {
+			return nil, fmt.Errorf("gtfs: trip %q: %w", row["trip_id"], err)
+		}
+		departure, err := parseGTFSTime(entry.startDate, row["departure_time"])
+		if err != nil {
+			return nil, fmt.Errorf("gtfs: trip %q: %w", row["trip_id"], err)
+		}
+		seq, _ := strconv.Atoi(row["stop_sequence"])
+		entry.timetable = append(entry.timetable, busservice.ScheduledStop{
+			Stop:             stop,
+			ScheduledArrival: arrival,
+			Dwell:            departure.Sub(arrival),
+		})
+		entry.sequence = append(entry.sequence, seq)
+	}
+
+	buses := make([]*busservice.Bus, 0, len(order))
+	for _, tripID := range order {
+		entry := trips[tripID]
+		sort.Stable(bySequence{entry.timetable, entry.sequence})
+		entry.bus.SetTimetable(entry.timetable)
+		buses = append(buses, entry.bus)
+	}
+	return buses, nil
+}
+
+// bySequence orders a Timetable by the GTFS stop_sequence it was read with, since stop_times.txt
+// rows aren't guaranteed to already be in sequence order.
+type bySequence struct {
+	tt  busservice.Timetable
+	seq []int
+}
+
+func (b bySequence) Len() int           { return len(b.tt) }
+func (b bySequence) Less(i, j int) bool { return b.seq[i] < b.seq[j] }
+func (b bySequence) Swap(i, j int) {
+	b.tt[i], b.tt[j] = b.tt[j], b.tt[i]
+	b.seq[i], b.seq[j] = b.seq[j], b.seq[i]
+}
+
+// parseGTFSTime combines a GTFS service start_date (YYYYMMDD) with a GTFS time-of-day
+// (HH:MM:SS, where hours may exceed 24 to represent service past midnight) into an absolute
+// time.Time.
+func parseGTFSTime(startDate, hms string) (time.Time, error) {
+	if startDate == "" || hms == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("20060102", startDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start_date %q: %w", startDate, err)
+	}
+	parts := strings.Split(hms, ":")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid time %q", hms)
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	s, err3 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", hms)
+	}
+	return date.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second), nil
+}
+
+// Dump re-serializes the given Buses as a GTFS feed, writing a zip archive to w.
+func Dump(w io.Writer, buses []*busservice.Bus) error {
+	zw := zip.NewWriter(w)
+
+	stops := map[string]*busservice.BusStop{}
+	var stopOrder []string
+	for _, bus := range buses {
+		for _, ss := range bus.Timetable() {
+			if _, ok := stops[ss.Stop.Name]; !ok {
+				stops[ss.Stop.Name] = ss.Stop
+				stopOrder = append(stopOrder, ss.Stop.Name)
+			}
+		}
+	}
+
+	if err := writeStops(zw, stopOrder, stops); err != nil {
+		return err
+	}
+	if err := writeRoutes(zw, buses); err != nil {
+		return err
+	}
+	if err := writeCalendar(zw, buses); err != nil {
+		return err
+	}
+	if err := writeTrips(zw, buses); err != nil {
+		return err
+	}
+	if err := writeStopTimes(zw, buses); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeStops(zw *zip.Writer, order []string, stops map[string]*busservice.BusStop) error {
+	f, err := zw.Create("stops.txt")
+	if err != nil {
+		return fmt.Errorf("gtfs: create stops.txt: %w", err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"stop_id", "stop_name", "stop_lat", "stop_lon"}); err != nil {
+		return err
+	}
+	for _, name := range order {
+		s := stops[name]
+		row := []string{
+			stopID(name),
+			s.Name,
+			strconv.FormatFloat(s.Position.Lat, 'f', -1, 64),
+			strconv.FormatFloat(s.Position.Lon, 'f', -1, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeRoutes(zw *zip.Writer, buses []*busservice.Bus) error {
+	f, err := zw.Create("routes.txt")
+	if err != nil {
+		return fmt.Errorf("gtfs: create routes.txt: %w", err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"route_id", "route_long_name"}); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, bus := range buses {
+		company := string(bus.Company)
+		if company == "" || seen[company] {
+			continue
+		}
+		seen[company] = true
+		if err := cw.Write([]string{routeID(company), company}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCalendar writes a single "weekday" service anchored to the earliest ScheduledArrival
+// across all buses, since every trip written by writeTrips shares that one service_id. Without
+// this, Load has no start_date to combine with stop_times.txt's HH:MM:SS and every reloaded
+// ScheduledArrival comes back as the zero time.
+func writeCalendar(zw *zip.Writer, buses []*busservice.Bus) error {
+	f, err := zw.Create("calendar.txt")
+	if err != nil {
+		return fmt.Errorf("gtfs: create calendar.txt: %w", err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"service_id", "start_date"}); err != nil {
+		return err
+	}
+
+	var startDate string
+	for _, bus := range buses {
+		for _, ss := range bus.Timetable() {
+			d := ss.ScheduledArrival.Format("20060102")
+			if startDate == "" || d < startDate {
+				startDate = d
+			}
+		}
+	}
+	if startDate != "" {
+		if err := cw.Write([]string{"weekday", startDate}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTrips(zw *zip.Writer, buses []*busservice.Bus) error {
+	f, err := zw.Create("trips.txt")
+	if err != nil {
+		return fmt.Errorf("gtfs: create trips.txt: %w", err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"route_id", "service_id", "trip_id", "trip_headsign"}); err != nil {
+		return err
+	}
+	for _, bus := range buses {
+		if err := cw.Write([]string{routeID(string(bus.Company)), "weekday", tripID(bus.Name()), bus.Name()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeStopTimes(zw *zip.Writer, buses []*busservice.Bus) error {
+	f, err := zw.Create("stop_times.txt")
+	if err != nil {
+		return fmt.Errorf("gtfs: create stop_times.txt: %w", err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence"}); err != nil {
+		return err
+	}
+	for _, bus := range buses {
+		for seq, ss := range bus.Timetable() {
+			departure := ss.ScheduledArrival.Add(ss.Dwell)
+			row := []string{
+				tripID(bus.Name()),
+				formatGTFSTime(ss.ScheduledArrival),
+				formatGTFSTime(departure),
+				stopID(ss.Stop.Name),
+				strconv.Itoa(seq),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatGTFSTime(t time.Time) string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+}
+
+// stopID, routeID and tripID derive stable GTFS ids from the human-readable names the
+// busservice types use, since Bus and BusStop have no separate id field of their own.
+func stopID(name string) string {
+	return slug(name)
+}
+
+func routeID(name string) string {
+	return slug(name)
+}
+
+func tripID(name string) string {
+	return slug(name)
+}
+
+func slug(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
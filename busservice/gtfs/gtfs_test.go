@@ -0,0 +1,68 @@
+package gtfs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mkock/busproject/busservice"
+)
+
+func TestLoadDumpRoundTrip(t *testing.T) {
+	depot := &busservice.BusStop{Name: "Depot", Position: busservice.Position{Lat: 55.67, Lon: 12.56}}
+	downtown := &busservice.BusStop{Name: "Downtown", Position: busservice.Position{Lat: 55.68, Lon: 12.58}}
+
+	arrival := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	bus := busservice.NewBus("Line 1")
+	bus.Company = "Acme Transit"
+	bus.SetTimetable(busservice.Timetable{
+		{Stop: depot, ScheduledArrival: arrival, Dwell: time.Minute},
+		{Stop: downtown, ScheduledArrival: arrival.Add(15 * time.Minute), Dwell: 2 * time.Minute},
+	})
+
+	f, err := os.CreateTemp(t.TempDir(), "feed-*.zip")
+	if err != nil {
+		t.Fatalf("CreateTemp() error = %v", err)
+	}
+	defer f.Close()
+
+	if err := Dump(f, []*busservice.Bus{bus}); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	buses, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(buses) != 1 {
+		t.Fatalf("len(Load()) = %d, want 1", len(buses))
+	}
+
+	got := buses[0]
+	if got.Name() != bus.Name() {
+		t.Errorf("Name() = %q, want %q", got.Name(), bus.Name())
+	}
+	if got.Company != bus.Company {
+		t.Errorf("Company = %q, want %q", got.Company, bus.Company)
+	}
+
+	tt := got.Timetable()
+	want := bus.Timetable()
+	if len(tt) != len(want) {
+		t.Fatalf("len(Timetable()) = %d, want %d", len(tt), len(want))
+	}
+	for i := range tt {
+		if tt[i].Stop.Name != want[i].Stop.Name {
+			t.Errorf("Timetable()[%d].Stop.Name = %q, want %q", i, tt[i].Stop.Name, want[i].Stop.Name)
+		}
+		if tt[i].Dwell != want[i].Dwell {
+			t.Errorf("Timetable()[%d].Dwell = %v, want %v", i, tt[i].Dwell, want[i].Dwell)
+		}
+		if !tt[i].ScheduledArrival.Equal(want[i].ScheduledArrival) {
+			t.Errorf("Timetable()[%d].ScheduledArrival = %v, want %v", i, tt[i].ScheduledArrival, want[i].ScheduledArrival)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package httpapi exposes a busservice.Simulation over HTTP, so that remote callers can query
+// upcoming arrivals, register prospects, and inspect a Bus's manifest or position.
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mkock/busproject/busservice"
+)
+
+// Handler exposes a busservice.Simulation over HTTP. Handler is safe for concurrent use: the
+// Simulation and the Bus/BusStop it holds each guard their own state with a sync.RWMutex.
+type Handler struct {
+	sim *busservice.Simulation
+}
+
+// NewHandler returns a Handler backed by sim.
+func NewHandler(sim *busservice.Simulation) *Handler {
+	return &Handler{sim: sim}
+}
+
+// Routes returns the http.Handler serving this API's endpoints.
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stops/", h.stops)
+	mux.HandleFunc("/buses/", h.buses)
+	return mux
+}
+
+// stops dispatches GET /stops/{name}/next-buses and POST /stops/{name}/prospects.
+func (h *Handler) stops(w http.ResponseWriter, r *http.Request) {
+	name, action, ok := splitResourcePath(r.URL.Path, "/stops/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	stop, ok := h.sim.Stop(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case action == "next-buses" && r.Method == http.MethodGet:
+		h.nextBuses(w, r, stop)
+	case action == "prospects" && r.Method == http.MethodPost:
+		h.registerProspect(w, r, stop)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// buses dispatches GET /buses/{name}/manifest and GET /buses/{name}/position.
+func (h *Handler) buses(w http.ResponseWriter, r *http.Request) {
+	name, action, ok := splitResourcePath(r.URL.Path, "/buses/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	bus, ok := h.sim.Bus(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case action == "manifest" && r.Method == http.MethodGet:
+		writeJSON(w, bus.Manifest())
+	case action == "position" && r.Method == http.MethodGet:
+		h.position(w, bus)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// splitResourcePath splits a path of the form prefix+"{name}/{action}" into name and action.
+func splitResourcePath(path, prefix string) (name, action string, ok bool) {
+	rest := strings.TrimPrefix(path, prefix)
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// nextBusArrival is the JSON shape returned by GET /stops/{name}/next-buses.
+type nextBusArrival struct {
+	Line        string `json:"line"`
+	ETA         string `json:"eta"`
+	Destination string `json:"destination"`
+}
+
+func (h *Handler) nextBuses(w http.ResponseWriter, r *http.Request, stop *busservice.BusStop) {
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	var arrivals []nextBusArrival
+	for _, bus := range h.sim.Buses() {
+		for _, ss := range bus.NextArrivals(stop, 0) {
+			dest := bus.Name()
+			if path := bus.RoutePath(); len(path) > 0 {
+				dest = path[len(path)-1].Name
+			}
+			arrivals = append(arrivals, nextBusArrival{
+				Line:        bus.Name(),
+				ETA:         ss.ScheduledArrival.Format("2006-01-02T15:04:05Z07:00"),
+				Destination: dest,
+			})
+		}
+	}
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].ETA < arrivals[j].ETA })
+	if limit > 0 && len(arrivals) > limit {
+		arrivals = arrivals[:limit]
+	}
+
+	writeJSON(w, arrivals)
+}
+
+// registerProspectRequest is the JSON body expected by POST /stops/{name}/prospects.
+type registerProspectRequest struct {
+	SSN         string `json:"ssn"`
+	Destination string `json:"destination"`
+}
+
+func (h *Handler) registerProspect(w http.ResponseWriter, r *http.Request, stop *busservice.BusStop) {
+	var req registerProspectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	dest, ok := h.sim.Stop(req.Destination)
+	if !ok {
+		http.Error(w, "unknown destination stop", http.StatusBadRequest)
+		return
+	}
+
+	stop.NotifyProspectArrival(busservice.Prospect{SSN: req.SSN, Destination: dest})
+	w.WriteHeader(http.StatusCreated)
+}
+
+// busPosition is the JSON shape returned by GET /buses/{name}/position.
+type busPosition struct {
+	Current string `json:"current"`
+	Next    string `json:"next,omitempty"`
+}
+
+func (h *Handler) position(w http.ResponseWriter, bus *busservice.Bus) {
+	pos := busPosition{Current: "not yet departed"}
+	if cur := bus.CurrentStop(); cur != nil {
+		pos.Current = cur.Name
+	}
+	if next := bus.NextStop(); next != nil {
+		pos.Next = next.Name
+	}
+	writeJSON(w, pos)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
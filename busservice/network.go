@@ -0,0 +1,111 @@
+package busservice
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Leg represents a single uninterrupted ride on one Bus from From to To, as part of a
+// multi-leg journey returned by Network.Plan.
+type Leg struct {
+	Bus  *Bus
+	From *BusStop
+	To   *BusStop
+}
+
+// Network owns a registered set of Buses and can plan multi-leg journeys across them, notifying
+// Prospects across lines that require a transfer, not just direct routes as
+// BusStop.NotifyProspectArrival does on its own. Network keeps no BusStops of its own: the
+// stops it plans over are those on the registered Buses' routes.
+type Network struct {
+	mu    sync.RWMutex
+	buses []*Bus
+}
+
+// NewNetwork returns an empty Network, ready to have Buses registered on it.
+func NewNetwork() *Network {
+	return &Network{}
+}
+
+// AddBus registers bus as part of the Network.
+func (n *Network) AddBus(bus *Bus) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.buses = append(n.buses, bus)
+}
+
+// Plan returns a multi-leg journey from `from` to `to`, transferring between Buses at a shared
+// BusStop when no single Bus services both ends directly. Plan considers at most one transfer:
+// a journey that needs two or more transfers yields an error.
+func (n *Network) Plan(from, to *BusStop) ([]Leg, error) {
+	n.mu.RLock()
+	buses := make([]*Bus, len(n.buses))
+	copy(buses, n.buses)
+	n.mu.RUnlock()
+
+	for _, bus := range buses {
+		stops := bus.Stops()
+		fromIdx, toIdx := indexOfStop(stops, from), indexOfStop(stops, to)
+		if fromIdx != -1 && toIdx != -1 && fromIdx <= toIdx {
+			return []Leg{{Bus: bus, From: from, To: to}}, nil
+		}
+	}
+
+	for _, first := range buses {
+		firstStops := first.Stops()
+		fromIdx := indexOfStop(firstStops, from)
+		if fromIdx == -1 {
+			continue
+		}
+		for _, transfer := range firstStops {
+			transferIdx := indexOfStop(firstStops, transfer)
+			if transferIdx < fromIdx {
+				continue
+			}
+			for _, second := range buses {
+				if second == first {
+					continue
+				}
+				secondStops := second.Stops()
+				transferIdx2, toIdx := indexOfStop(secondStops, transfer), indexOfStop(secondStops, to)
+				if transferIdx2 == -1 || toIdx == -1 || transferIdx2 > toIdx {
+					continue
+				}
+				return []Leg{
+					{Bus: first, From: from, To: transfer},
+					{Bus: second, From: transfer, To: to},
+				}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("busservice: no route found from %q to %q", from.Name, to.Name)
+}
+
+// indexOfStop returns the index of the first occurrence of stop within stops, or -1 if stop
+// isn't among them.
+func indexOfStop(stops []*BusStop, stop *BusStop) int {
+	for i, s := range stops {
+		if s.Equals(stop) {
+			return i
+		}
+	}
+	return -1
+}
+
+// NotifyProspectArrival registers p at the BusStop `at`, handling direct routes exactly like
+// BusStop.NotifyProspectArrival, but also alerting the first Bus of a transfer route when no
+// direct Bus reaches p's Destination.
+func (n *Network) NotifyProspectArrival(at *BusStop, p Prospect) error {
+	at.NotifyProspectArrival(p)
+
+	legs, err := n.Plan(at, p.Destination)
+	if err != nil {
+		return err
+	}
+	if len(legs) < 2 {
+		return nil // A direct Bus already got notified above.
+	}
+	legs[0].Bus.NotifyBoardingIntent(at)
+	return nil
+}
@@ -0,0 +1,80 @@
+package busservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkock/busproject/busservice/clock"
+)
+
+func TestBusScheduleAdherence(t *testing.T) {
+	start := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	mc := clock.NewManual(start)
+
+	first := &BusStop{Name: "First"}
+	second := &BusStop{Name: "Second"}
+	third := &BusStop{Name: "Third"}
+
+	bus := NewBus("line-1")
+	bus.SetClock(mc)
+	bus.SetTimetable(Timetable{
+		{Stop: first, ScheduledArrival: start, Dwell: time.Minute},
+		{Stop: second, ScheduledArrival: start.Add(10 * time.Minute), Dwell: time.Minute},
+		{Stop: third, ScheduledArrival: start.Add(20 * time.Minute), Dwell: time.Minute},
+	})
+
+	bus.Go() // Arrives at First, exactly on schedule.
+	mc.Advance(15 * time.Minute)
+	bus.Go() // Arrives at Second, 5 minutes late.
+	mc.Advance(3 * time.Minute)
+	bus.Go() // Arrives at Third, 2 minutes early.
+
+	visits := bus.Visits()
+	if len(visits) != 3 {
+		t.Fatalf("len(Visits()) = %d, want 3", len(visits))
+	}
+
+	if got := visits[0].ArrivalStatus; got != StatusOnTime {
+		t.Errorf("visits[0].ArrivalStatus = %v, want StatusOnTime", got)
+	}
+	if got := visits[1].ArrivalStatus; got != StatusDelayed {
+		t.Errorf("visits[1].ArrivalStatus = %v, want StatusDelayed", got)
+	}
+	if got := visits[2].ArrivalStatus; got != StatusEarly {
+		t.Errorf("visits[2].ArrivalStatus = %v, want StatusEarly", got)
+	}
+
+	if got, want := bus.Delay(second), 5*time.Minute; got != want {
+		t.Errorf("Delay(second) = %v, want %v", got, want)
+	}
+}
+
+func TestBusSkipStop(t *testing.T) {
+	start := time.Date(2026, 7, 25, 8, 0, 0, 0, time.UTC)
+	mc := clock.NewManual(start)
+
+	first := &BusStop{Name: "First"}
+	second := &BusStop{Name: "Second"}
+
+	bus := NewBus("line-1")
+	bus.SetClock(mc)
+	bus.SetTimetable(Timetable{
+		{Stop: first, ScheduledArrival: start, Dwell: time.Minute},
+		{Stop: second, ScheduledArrival: start.Add(10 * time.Minute), Dwell: time.Minute},
+	})
+
+	if more := bus.SkipStop(); !more {
+		t.Fatalf("SkipStop() = false, want true (more stops remain)")
+	}
+
+	visits := bus.Visits()
+	if len(visits) != 1 {
+		t.Fatalf("len(Visits()) = %d, want 1", len(visits))
+	}
+	if got := visits[0].ArrivalStatus; got != StatusCancelled {
+		t.Errorf("visits[0].ArrivalStatus = %v, want StatusCancelled", got)
+	}
+	if got := visits[0].Stop; got != first {
+		t.Errorf("visits[0].Stop = %v, want %v", got, first)
+	}
+}
@@ -0,0 +1,60 @@
+package busservice
+
+import "sync"
+
+// Simulation aggregates the Buses and BusStops that make up a running simulation, keyed by
+// name, so that external callers such as busservice/httpapi can look them up concurrently.
+type Simulation struct {
+	mu    sync.RWMutex
+	buses map[string]*Bus
+	stops map[string]*BusStop
+}
+
+// NewSimulation returns an empty Simulation, ready to register Buses and BusStops on.
+func NewSimulation() *Simulation {
+	return &Simulation{
+		buses: make(map[string]*Bus),
+		stops: make(map[string]*BusStop),
+	}
+}
+
+// AddBus registers bus with the Simulation under its name.
+func (s *Simulation) AddBus(bus *Bus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buses[bus.Name()] = bus
+}
+
+// AddStop registers stop with the Simulation under its name.
+func (s *Simulation) AddStop(stop *BusStop) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops[stop.Name] = stop
+}
+
+// Bus returns the Bus registered under the given name, if any.
+func (s *Simulation) Bus(name string) (*Bus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.buses[name]
+	return b, ok
+}
+
+// Stop returns the BusStop registered under the given name, if any.
+func (s *Simulation) Stop(name string) (*BusStop, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.stops[name]
+	return st, ok
+}
+
+// Buses returns every Bus registered with the Simulation.
+func (s *Simulation) Buses() []*Bus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	buses := make([]*Bus, 0, len(s.buses))
+	for _, b := range s.buses {
+		buses = append(buses, b)
+	}
+	return buses
+}